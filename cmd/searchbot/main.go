@@ -1,27 +1,60 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"os"
 	"path/filepath"
 	"searchbot/pkg/display"
 	"searchbot/pkg/search"
+	"searchbot/pkg/search/filter"
+	"searchbot/pkg/search/index"
+	"strings"
 )
 
+// stringList collects the values of a repeatable flag, e.g. -include.
+type stringList []string
+
+func (s *stringList) String() string { return strings.Join(*s, ",") }
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
 func main() {
 	// Define flags with new defaults
 	recursive := flag.Bool("nr", false, "Non-recursive search (by default search is recursive)")
 	exactMatch := flag.Bool("e", false, "Match exact filename (by default matches substrings)")
 	caseSensitive := flag.Bool("i", false, "Case insensitive search (by default search is case sensitive)")
 
+	var includePatterns, excludePatterns, mimeTypes stringList
+	flag.Var(&includePatterns, "include", "Only include paths matching this gitignore-style glob (repeatable)")
+	flag.Var(&excludePatterns, "exclude", "Exclude paths matching this gitignore-style glob (repeatable)")
+	content := flag.String("content", "", "Also search file contents for this regular expression")
+	maxFileSize := flag.Int64("max-size", 0, "Skip content search for files larger than this many bytes (0 = no limit)")
+	flag.Var(&mimeTypes, "mime-type", "Restrict content search to this MIME type prefix (repeatable, default: text/*)")
+	searchArchives := flag.Bool("archives", false, "Also search inside .zip, .tar, .tar.gz, and .tar.bz2 files")
+	refresh := flag.Bool("refresh", false, "Bypass the directory listing cache and re-read the tree from disk")
+	outputFormat := flag.String("o", "table", "Output format: table, json, ndjson, null, tree")
+
 	flag.Usage = func() {
 		fmt.Fprintf(os.Stderr, "Usage: s [options] <search_pattern> [directory]\n")
 		fmt.Fprintf(os.Stderr, "\nOptions:\n")
-		fmt.Fprintf(os.Stderr, "  -nr   Non-recursive search (by default search is recursive)\n")
-		fmt.Fprintf(os.Stderr, "  -e    Match exact filename (by default matches substrings)\n")
-		fmt.Fprintf(os.Stderr, "  -i    Case insensitive search (by default search is case sensitive)\n")
+		fmt.Fprintf(os.Stderr, "  -nr          Non-recursive search (by default search is recursive)\n")
+		fmt.Fprintf(os.Stderr, "  -e           Match exact filename (by default matches substrings)\n")
+		fmt.Fprintf(os.Stderr, "  -i           Case insensitive search (by default search is case sensitive)\n")
+		fmt.Fprintf(os.Stderr, "  -include     Only include paths matching this glob (repeatable)\n")
+		fmt.Fprintf(os.Stderr, "  -exclude     Exclude paths matching this glob (repeatable)\n")
+		fmt.Fprintf(os.Stderr, "  -content     Also search file contents for this regular expression\n")
+		fmt.Fprintf(os.Stderr, "  -max-size    Skip content search for files larger than this many bytes\n")
+		fmt.Fprintf(os.Stderr, "  -mime-type   Restrict content search to this MIME type prefix (repeatable)\n")
+		fmt.Fprintf(os.Stderr, "  -archives    Also search inside .zip, .tar, .tar.gz, and .tar.bz2 files\n")
+		fmt.Fprintf(os.Stderr, "  -refresh     Bypass the directory listing cache and re-read the tree from disk\n")
+		fmt.Fprintf(os.Stderr, "  -o           Output format: table, json, ndjson, null, tree (default table)\n")
 		fmt.Fprintf(os.Stderr, "\nIf directory is not specified, searches in current directory\n")
+		fmt.Fprintf(os.Stderr, "Patterns are also loaded from a .searchbotignore file discovered upward from the search directory.\n")
 	}
 	flag.Parse()
 
@@ -51,21 +84,75 @@ func main() {
 		os.Exit(1)
 	}
 
+	ignorePatterns, err := filter.LoadIgnoreFile(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading .searchbotignore: %v\n", err)
+		os.Exit(1)
+	}
+
 	// Create search options with new defaults
 	opts := search.SearchOptions{
-		Recursive:     !*recursive,     // Default true, -nr flag makes it false
-		ExactMatch:    *exactMatch,     // Default false
-		CaseSensitive: !*caseSensitive, // Default true, -i flag makes it false
+		Recursive:       !*recursive,     // Default true, -nr flag makes it false
+		ExactMatch:      *exactMatch,     // Default false
+		CaseSensitive:   !*caseSensitive, // Default true, -i flag makes it false
+		IncludePatterns: includePatterns,
+		ExcludePatterns: append(append([]string{}, ignorePatterns...), excludePatterns...),
+		Content:         *content,
+		MaxFileSize:     *maxFileSize,
+		MimeTypes:       mimeTypes,
+		SearchArchives:  *searchArchives,
 	}
 
-	// Start the search with a status message
-	fmt.Printf("Searching for '%s' in %s...\n", pattern, root)
+	formatter, err := display.FormatterFor(*outputFormat)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 
-	results, err := search.SearchFiles(pattern, root, opts)
+	// Start the search with a status message. This goes to stderr, not
+	// stdout, so json/ndjson/null/tree output stays pipeable.
+	fmt.Fprintf(os.Stderr, "Searching for '%s' in %s...\n", pattern, root)
+
+	// Streaming formats render each result as it arrives instead of
+	// buffering the whole search in memory first.
+	if streamFormatter, ok := formatter.(display.StreamFormatter); ok {
+		resultCh, errCh := search.SearchFilesStream(context.Background(), pattern, root, opts)
+		for result := range resultCh {
+			if err := streamFormatter.FormatResult(os.Stdout, result); err != nil {
+				fmt.Fprintf(os.Stderr, "Error writing result: %v\n", err)
+				os.Exit(1)
+			}
+		}
+		if err := <-errCh; err != nil {
+			fmt.Fprintf(os.Stderr, "Error searching files: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	var results []search.SearchResult
+	if *refresh {
+		results, err = search.SearchFiles(pattern, root, opts)
+	} else {
+		cachePath, cacheErr := index.DefaultCachePath()
+		if cacheErr != nil {
+			fmt.Fprintf(os.Stderr, "Error resolving cache path: %v\n", cacheErr)
+			os.Exit(1)
+		}
+		searcher, cacheErr := search.NewCachedSearcher(cachePath)
+		if cacheErr != nil {
+			fmt.Fprintf(os.Stderr, "Error loading search cache: %v\n", cacheErr)
+			os.Exit(1)
+		}
+		results, err = searcher.Search(pattern, root, opts)
+	}
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error searching files: %v\n", err)
 		os.Exit(1)
 	}
 
-	display.PrintResults(results)
+	if err := formatter.Format(os.Stdout, results); err != nil {
+		fmt.Fprintf(os.Stderr, "Error formatting results: %v\n", err)
+		os.Exit(1)
+	}
 }
@@ -2,8 +2,9 @@ package display
 
 import (
 	"fmt"
+	"io"
+	"os"
 	"searchbot/pkg/search"
-	"sort"
 	"strings"
 	"unicode/utf8"
 
@@ -35,40 +36,42 @@ func FormatSize(size int64) string {
 	return fmt.Sprintf("%s%.1f %cB", sign, float64(size)/float64(div), "KMGTPE"[exp])
 }
 
-// PrintResults displays the search results in a formatted way
+// PrintResults displays the search results in a formatted way. It's a
+// thin convenience wrapper around TableFormatter for callers that don't
+// need to choose an output format; see Formatter for the general API.
 func PrintResults(results []search.SearchResult) {
-	if len(results) == 0 {
-		color.Yellow("\nNo files found")
-		return
-	}
-
-	// Sort results by name
-	sort.Slice(results, func(i, j int) bool {
-		return results[i].Name < results[j].Name
-	})
+	TableFormatter{}.Format(os.Stdout, results)
+}
 
-	// Calculate total size
-	var totalSize int64
+// hasContentMatches reports whether any result carries content matches,
+// meaning the results came from a content (not just filename) search.
+func hasContentMatches(results []search.SearchResult) bool {
 	for _, result := range results {
-		totalSize += result.Size
+		if len(result.Matches) > 0 {
+			return true
+		}
 	}
+	return false
+}
 
-	color.Green("\nFound %d files (Total size: %s)\n", len(results), FormatSize(totalSize))
-	fmt.Println(strings.Repeat("-", 100))
-
-	// Print header
-	color.Blue("%-50s %-20s %-15s %s\n", "NAME", "SIZE", "MODIFIED", "PATH")
-	fmt.Println(strings.Repeat("-", 100))
+// printContentMatches renders results in grep-style, one line per match,
+// with the matched span highlighted.
+func printContentMatches(w io.Writer, results []search.SearchResult) {
+	path := color.New(color.FgMagenta).SprintFunc()
+	lineNo := color.New(color.FgGreen).SprintFunc()
+	highlight := color.New(color.FgRed, color.Bold).SprintFunc()
 
 	for _, result := range results {
-		fmt.Printf("%-50s %-20s %-15s %s\n",
-			truncateString(result.Name, 47),
-			FormatSize(result.Size),
-			result.ModTime,
-			result.Path,
-		)
+		for _, m := range result.Matches {
+			fmt.Fprintf(w, "%s:%s:%s%s%s\n",
+				path(result.Path),
+				lineNo(m.LineNumber),
+				m.Line[:m.Start],
+				highlight(m.Line[m.Start:m.End]),
+				m.Line[m.End:],
+			)
+		}
 	}
-	fmt.Println(strings.Repeat("-", 100))
 }
 
 // truncateString truncates a string if it's longer than maxLen
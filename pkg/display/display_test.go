@@ -189,3 +189,32 @@ func TestPrintResults(t *testing.T) {
 	// Restore the original stdout
 	os.Stdout = oldStdout
 }
+
+func TestPrintResultsWithContentMatches(t *testing.T) {
+	oldStdout := os.Stdout
+	r, w, _ := os.Pipe()
+	os.Stdout = w
+
+	PrintResults([]search.SearchResult{
+		{
+			Path: "/path/to/notes.txt",
+			Name: "notes.txt",
+			Matches: []search.LineMatch{
+				{LineNumber: 2, Line: "see the needle here", Start: 8, End: 14},
+			},
+		},
+	})
+
+	w.Close()
+	os.Stdout = oldStdout
+
+	var buf bytes.Buffer
+	io.Copy(&buf, r)
+	output := buf.String()
+
+	for _, expected := range []string{"/path/to/notes.txt", "2", "see the ", "needle", " here"} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Output should contain %q but didn't.\nFull output:\n%s", expected, output)
+		}
+	}
+}
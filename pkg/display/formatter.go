@@ -0,0 +1,219 @@
+package display
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"searchbot/pkg/search"
+	"sort"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// Formatter renders a batch of search results to w.
+type Formatter interface {
+	Format(w io.Writer, results []search.SearchResult) error
+}
+
+// StreamFormatter is implemented by formatters that can render results one
+// at a time as they arrive, rather than needing the full batch up front.
+// NDJSONFormatter and NullFormatter implement this so the CLI can pipe
+// partial results from very large searches without buffering them all in
+// memory.
+type StreamFormatter interface {
+	Formatter
+	FormatResult(w io.Writer, result search.SearchResult) error
+}
+
+// FormatterFor returns the Formatter registered under name (one of
+// "table", "json", "ndjson", "null", "tree").
+func FormatterFor(name string) (Formatter, error) {
+	switch name {
+	case "table":
+		return TableFormatter{}, nil
+	case "json":
+		return JSONFormatter{}, nil
+	case "ndjson":
+		return NDJSONFormatter{}, nil
+	case "null":
+		return NullFormatter{}, nil
+	case "tree":
+		return TreeFormatter{}, nil
+	default:
+		return nil, fmt.Errorf("unknown output format %q", name)
+	}
+}
+
+// TableFormatter renders results as the classic aligned-column table.
+type TableFormatter struct{}
+
+// Format implements Formatter.
+func (TableFormatter) Format(w io.Writer, results []search.SearchResult) error {
+	if len(results) == 0 {
+		color.New(color.FgYellow).Fprintln(w, "\nNo files found")
+		return nil
+	}
+
+	if hasContentMatches(results) {
+		printContentMatches(w, results)
+		return nil
+	}
+
+	sorted := make([]search.SearchResult, len(results))
+	copy(sorted, results)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	var totalSize int64
+	for _, result := range sorted {
+		totalSize += result.Size
+	}
+
+	color.New(color.FgGreen).Fprintf(w, "\nFound %d files (Total size: %s)\n", len(sorted), FormatSize(totalSize))
+	fmt.Fprintln(w, strings.Repeat("-", 100))
+
+	color.New(color.FgBlue).Fprintf(w, "%-50s %-20s %-15s %s\n", "NAME", "SIZE", "MODIFIED", "PATH")
+	fmt.Fprintln(w, strings.Repeat("-", 100))
+
+	for _, result := range sorted {
+		fmt.Fprintf(w, "%-50s %-20s %-15s %s\n",
+			truncateString(result.Name, 47),
+			FormatSize(result.Size),
+			result.ModTime,
+			result.Path,
+		)
+	}
+	fmt.Fprintln(w, strings.Repeat("-", 100))
+	return nil
+}
+
+// JSONFormatter renders results as a single JSON array.
+type JSONFormatter struct{}
+
+// Format implements Formatter.
+func (JSONFormatter) Format(w io.Writer, results []search.SearchResult) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
+
+// NDJSONFormatter renders one JSON object per line, suitable for piping
+// into jq or xargs.
+type NDJSONFormatter struct{}
+
+// Format implements Formatter.
+func (f NDJSONFormatter) Format(w io.Writer, results []search.SearchResult) error {
+	for _, result := range results {
+		if err := f.FormatResult(w, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormatResult implements StreamFormatter.
+func (NDJSONFormatter) FormatResult(w io.Writer, result search.SearchResult) error {
+	return json.NewEncoder(w).Encode(result)
+}
+
+// NullFormatter renders each result's path NUL-separated, for piping into
+// "xargs -0".
+type NullFormatter struct{}
+
+// Format implements Formatter.
+func (f NullFormatter) Format(w io.Writer, results []search.SearchResult) error {
+	for _, result := range results {
+		if err := f.FormatResult(w, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormatResult implements StreamFormatter.
+func (NullFormatter) FormatResult(w io.Writer, result search.SearchResult) error {
+	_, err := fmt.Fprintf(w, "%s\x00", result.Path)
+	return err
+}
+
+// TreeFormatter renders results as an indented directory tree, similar to
+// the "tree" command, colored by file type.
+type TreeFormatter struct{}
+
+// Format implements Formatter.
+func (TreeFormatter) Format(w io.Writer, results []search.SearchResult) error {
+	root := &treeNode{children: map[string]*treeNode{}}
+	for _, result := range results {
+		root.insert(strings.Split(filepath.ToSlash(result.Path), "/"))
+	}
+	root.print(w, "")
+	return nil
+}
+
+// treeNode is one path segment in the tree built from a set of results.
+type treeNode struct {
+	children map[string]*treeNode
+	isFile   bool
+}
+
+func (n *treeNode) insert(segments []string) {
+	cur := n
+	for i, seg := range segments {
+		if seg == "" {
+			continue
+		}
+		child, ok := cur.children[seg]
+		if !ok {
+			child = &treeNode{children: map[string]*treeNode{}}
+			cur.children[seg] = child
+		}
+		if i == len(segments)-1 {
+			child.isFile = true
+		}
+		cur = child
+	}
+}
+
+func (n *treeNode) print(w io.Writer, prefix string) {
+	names := make([]string, 0, len(n.children))
+	for name := range n.children {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		child := n.children[name]
+		connector, childPrefix := "├── ", prefix+"│   "
+		if i == len(names)-1 {
+			connector, childPrefix = "└── ", prefix+"    "
+		}
+
+		c := color.New(color.FgBlue, color.Bold)
+		if child.isFile {
+			c = colorForExt(name)
+		}
+		fmt.Fprintf(w, "%s%s%s\n", prefix, connector, c.Sprint(name))
+
+		child.print(w, childPrefix)
+	}
+}
+
+// colorForExt picks a tree display color by file extension, loosely
+// mirroring common "tree"/"ls --color" conventions.
+func colorForExt(name string) *color.Color {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".go", ".py", ".js", ".ts", ".rs", ".c", ".cpp":
+		return color.New(color.FgGreen)
+	case ".jpg", ".jpeg", ".png", ".gif", ".mp4", ".mov":
+		return color.New(color.FgMagenta)
+	case ".pdf", ".doc", ".docx", ".txt", ".md":
+		return color.New(color.FgYellow)
+	case ".zip", ".tar", ".gz", ".bz2":
+		return color.New(color.FgRed)
+	default:
+		return color.New(color.FgWhite)
+	}
+}
@@ -0,0 +1,86 @@
+package display
+
+import (
+	"bytes"
+	"encoding/json"
+	"searchbot/pkg/search"
+	"strings"
+	"testing"
+)
+
+func sampleResults() []search.SearchResult {
+	return []search.SearchResult{
+		{Path: "/tmp/a/one.txt", Name: "one.txt", Size: 10, ModTime: "2024-03-20 10:00:00"},
+		{Path: "/tmp/b/two.go", Name: "two.go", Size: 20, ModTime: "2024-03-20 11:00:00"},
+	}
+}
+
+func TestFormatterFor(t *testing.T) {
+	for _, name := range []string{"table", "json", "ndjson", "null", "tree"} {
+		if _, err := FormatterFor(name); err != nil {
+			t.Errorf("FormatterFor(%q) error = %v", name, err)
+		}
+	}
+	if _, err := FormatterFor("yaml"); err == nil {
+		t.Error("FormatterFor(\"yaml\") should have returned an error")
+	}
+}
+
+func TestJSONFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (JSONFormatter{}).Format(&buf, sampleResults()); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	var decoded []search.SearchResult
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("output is not valid JSON: %v", err)
+	}
+	if len(decoded) != 2 {
+		t.Errorf("decoded %d results, want 2", len(decoded))
+	}
+}
+
+func TestNDJSONFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (NDJSONFormatter{}).Format(&buf, sampleResults()); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2", len(lines))
+	}
+	for _, line := range lines {
+		var result search.SearchResult
+		if err := json.Unmarshal([]byte(line), &result); err != nil {
+			t.Errorf("line %q is not valid JSON: %v", line, err)
+		}
+	}
+}
+
+func TestNullFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (NullFormatter{}).Format(&buf, sampleResults()); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	paths := strings.Split(strings.TrimRight(buf.String(), "\x00"), "\x00")
+	if len(paths) != 2 || paths[0] != "/tmp/a/one.txt" || paths[1] != "/tmp/b/two.go" {
+		t.Errorf("NullFormatter output = %q, want two NUL-separated paths", buf.String())
+	}
+}
+
+func TestTreeFormatter(t *testing.T) {
+	var buf bytes.Buffer
+	if err := (TreeFormatter{}).Format(&buf, sampleResults()); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	output := buf.String()
+	for _, want := range []string{"tmp", "a", "b", "one.txt", "two.go"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("TreeFormatter output should contain %q.\nGot:\n%s", want, output)
+		}
+	}
+}
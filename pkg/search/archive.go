@@ -0,0 +1,110 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"regexp"
+
+	"searchbot/pkg/vfs"
+)
+
+// searchArchiveFile opens path as an archive (the caller has already
+// confirmed a vfs handler is registered for its extension) and searches
+// its contents, reporting hits through resultCh with paths of the form
+// "<path>!<entry>".
+func searchArchiveFile(ctx context.Context, path string, pattern string, opts SearchOptions, contentRe *regexp.Regexp, resultCh chan<- SearchResult) {
+	opener, ok := vfs.OpenerFor(path)
+	if !ok {
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return
+	}
+
+	fsys, err := opener(f, info.Size())
+	if err != nil {
+		return
+	}
+
+	walkArchiveFS(ctx, fsys, path, pattern, opts, contentRe, resultCh)
+}
+
+// walkArchiveFS walks fsys, reporting entries whose name matches pattern
+// (and, when contentRe is set, whose content matches it), and recursing
+// into any nested archives it finds when opts.SearchArchives is set.
+// Unlike processDir, it applies no include/exclude patterns, does not skip
+// hidden entries, and does not report a nested archive entry itself.
+func walkArchiveFS(ctx context.Context, fsys vfs.FS, displayPrefix string, pattern string, opts SearchOptions, contentRe *regexp.Regexp, resultCh chan<- SearchResult) {
+	fsys.Walk(".", func(entryPath string, info vfs.FileInfo, err error) error {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		if err != nil || info == nil || info.IsDir() {
+			return nil
+		}
+
+		displayPath := displayPrefix + "!" + entryPath
+
+		if nestedOpener, ok := vfs.OpenerFor(entryPath); ok && opts.SearchArchives {
+			if data, err := readAll(fsys, entryPath); err == nil {
+				if nested, err := nestedOpener(bytes.NewReader(data), int64(len(data))); err == nil {
+					walkArchiveFS(ctx, nested, displayPath, pattern, opts, contentRe, resultCh)
+				}
+			}
+			return nil
+		}
+
+		if !matchName(info.Name(), pattern, opts) {
+			return nil
+		}
+
+		var matches []LineMatch
+		if contentRe != nil {
+			data, err := readAll(fsys, entryPath)
+			if err != nil {
+				return nil
+			}
+			if opts.MaxFileSize > 0 && int64(len(data)) > opts.MaxFileSize {
+				return nil
+			}
+			matches = matchContentBytes(data, contentRe, opts.MimeTypes)
+			if len(matches) == 0 {
+				return nil
+			}
+		}
+
+		select {
+		case resultCh <- SearchResult{
+			Path:    displayPath,
+			Name:    info.Name(),
+			Size:    info.Size(),
+			ModTime: info.ModTime().Format("2006-01-02 15:04:05"),
+			Matches: matches,
+		}:
+		case <-ctx.Done():
+		}
+		return nil
+	})
+}
+
+func readAll(fsys vfs.FS, entryPath string) ([]byte, error) {
+	rc, err := fsys.Open(entryPath)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
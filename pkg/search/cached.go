@@ -0,0 +1,226 @@
+package search
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"searchbot/pkg/search/filter"
+	"searchbot/pkg/search/index"
+	"searchbot/pkg/vfs"
+	"strings"
+)
+
+// CachedSearcher searches directory trees using a persistent, inode-keyed
+// index of directory listings (see pkg/search/index), so repeat searches
+// over an unchanged tree can skip re-reading directories that haven't
+// been modified since the last run.
+type CachedSearcher struct {
+	idx *index.Index
+}
+
+// NewCachedSearcher loads (or creates) the index at cachePath.
+func NewCachedSearcher(cachePath string) (*CachedSearcher, error) {
+	idx, err := index.Load(cachePath)
+	if err != nil {
+		return nil, err
+	}
+	return &CachedSearcher{idx: idx}, nil
+}
+
+// Invalidate drops the cached listing for path (and everything beneath
+// it), forcing the next Search to re-read it from disk.
+func (c *CachedSearcher) Invalidate(path string) {
+	c.idx.Invalidate(path)
+}
+
+// Search behaves like the package-level SearchFiles, but consults (and
+// updates) the cache instead of always calling os.ReadDir.
+func (c *CachedSearcher) Search(pattern string, root string, opts SearchOptions) ([]SearchResult, error) {
+	if pattern == "" {
+		return nil, ErrEmptyPattern
+	}
+	if _, err := os.Stat(root); err != nil {
+		return nil, ErrInvalidPath
+	}
+
+	includeMatcher, err := filter.New(opts.IncludePatterns)
+	if err != nil {
+		return nil, err
+	}
+	excludeMatcher, err := filter.New(opts.ExcludePatterns)
+	if err != nil {
+		return nil, err
+	}
+
+	var contentRe *regexp.Regexp
+	if opts.Content != "" {
+		contentRe, err = regexp.Compile(opts.Content)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var results []SearchResult
+	if err := c.walk(root, root, pattern, opts, includeMatcher, excludeMatcher, contentRe, &results); err != nil {
+		return nil, err
+	}
+
+	if err := c.idx.Save(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// walk recursively searches dir, consulting the index for its listing
+// before falling back to os.ReadDir.
+func (c *CachedSearcher) walk(root, dir string, pattern string, opts SearchOptions, includeMatcher, excludeMatcher *filter.Matcher, contentRe *regexp.Regexp, results *[]SearchResult) error {
+	isRoot := dir == root
+	if !isRoot && shouldSkipDirectory(dir) {
+		return nil
+	}
+
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil
+	}
+
+	dirents, err := c.listDir(dir, info)
+	if err != nil {
+		return nil
+	}
+
+	for _, d := range dirents {
+		path := filepath.Join(dir, d.Name)
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			relPath = path
+		}
+
+		if d.Mode.IsDir() {
+			if !opts.Recursive {
+				continue
+			}
+			if shouldSkipDirectory(path) {
+				continue
+			}
+			if excludeMatcher.Match(relPath, true) {
+				continue
+			}
+			if err := c.walk(root, path, pattern, opts, includeMatcher, excludeMatcher, contentRe, results); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if strings.HasPrefix(d.Name, ".") {
+			continue
+		}
+
+		if opts.SearchArchives {
+			if _, ok := vfs.OpenerFor(d.Name); ok {
+				c.searchArchive(path, pattern, opts, contentRe, results)
+			}
+		}
+
+		if !matchName(d.Name, pattern, opts) {
+			continue
+		}
+		if len(opts.IncludePatterns) > 0 && !includeMatcher.Match(relPath, false) {
+			continue
+		}
+		if excludeMatcher.Match(relPath, false) {
+			continue
+		}
+
+		var matches []LineMatch
+		if contentRe != nil {
+			if opts.MaxFileSize > 0 && d.Size > opts.MaxFileSize {
+				continue
+			}
+			matches, err = searchFileContent(path, contentRe, opts.MimeTypes)
+			if err != nil {
+				continue
+			}
+			if len(matches) == 0 {
+				continue
+			}
+		}
+
+		*results = append(*results, SearchResult{
+			Path:    path,
+			Name:    d.Name,
+			Size:    d.Size,
+			ModTime: d.ModTime.Format("2006-01-02 15:04:05"),
+			Matches: matches,
+		})
+	}
+
+	return nil
+}
+
+// searchArchive runs the shared archive-descent logic (see archive.go)
+// over path and appends whatever it finds to results. walk is a plain
+// synchronous recursion with no consumer draining a results channel, so
+// unlike the concurrent walker's processDir, this drains searchArchiveFile's
+// channel itself via a throwaway goroutine.
+func (c *CachedSearcher) searchArchive(path, pattern string, opts SearchOptions, contentRe *regexp.Regexp, results *[]SearchResult) {
+	archiveCh := make(chan SearchResult)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for r := range archiveCh {
+			*results = append(*results, r)
+		}
+	}()
+
+	searchArchiveFile(context.Background(), path, pattern, opts, contentRe, archiveCh)
+	close(archiveCh)
+	<-done
+}
+
+// listDir returns dir's entries, reusing the cached listing when dir's
+// mtime hasn't changed since it was recorded, and refreshing (and
+// re-caching) it otherwise.
+func (c *CachedSearcher) listDir(dir string, info os.FileInfo) ([]index.Dirent, error) {
+	id := index.FileIDFor(info)
+
+	if cached, ok := c.idx.Get(dir, id); ok && cached.ModTime.Equal(info.ModTime()) {
+		if cached.Path != dir {
+			// dir was renamed since this entry was cached; refresh the
+			// path we'd match against in a future Invalidate call.
+			cached.Path = dir
+			c.idx.Set(dir, id, cached)
+		}
+		return cached.Entries, nil
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	dirents := make([]index.Dirent, 0, len(entries))
+	for _, entry := range entries {
+		fi, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		dirents = append(dirents, index.Dirent{
+			Name:    fi.Name(),
+			Mode:    fi.Mode(),
+			Size:    fi.Size(),
+			ModTime: fi.ModTime(),
+		})
+	}
+
+	c.idx.Set(dir, id, index.DirEntry{
+		Path:    dir,
+		ID:      id,
+		ModTime: info.ModTime(),
+		Entries: dirents,
+	})
+
+	return dirents, nil
+}
@@ -0,0 +1,79 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCachedSearcherFindsFilesAndReusesCache(t *testing.T) {
+	tempDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(tempDir, "report-2024.pdf"), []byte("pdf"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	cachePath := filepath.Join(t.TempDir(), "index.gob")
+
+	searcher, err := NewCachedSearcher(cachePath)
+	if err != nil {
+		t.Fatalf("NewCachedSearcher() error = %v", err)
+	}
+
+	opts := SearchOptions{Recursive: true, CaseSensitive: true}
+
+	results, err := searcher.Search(".pdf", tempDir, opts)
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(results) != 1 || filepath.Base(results[0].Path) != "report-2024.pdf" {
+		t.Fatalf("Search() = %+v, want a single report-2024.pdf result", results)
+	}
+
+	if _, err := os.Stat(cachePath); err != nil {
+		t.Fatalf("Search() should have persisted the cache file: %v", err)
+	}
+
+	// A fresh searcher loading the same cache file should find the same
+	// result from the cached listing, since the directory is unchanged.
+	reloaded, err := NewCachedSearcher(cachePath)
+	if err != nil {
+		t.Fatalf("NewCachedSearcher() on reload error = %v", err)
+	}
+	results, err = reloaded.Search(".pdf", tempDir, opts)
+	if err != nil {
+		t.Fatalf("Search() after reload error = %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("Search() after reload = %d results, want 1", len(results))
+	}
+}
+
+func TestCachedSearcherInvalidate(t *testing.T) {
+	tempDir := t.TempDir()
+	cachePath := filepath.Join(t.TempDir(), "index.gob")
+
+	searcher, err := NewCachedSearcher(cachePath)
+	if err != nil {
+		t.Fatalf("NewCachedSearcher() error = %v", err)
+	}
+
+	if _, err := searcher.Search("nothing", tempDir, SearchOptions{Recursive: true}); err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+
+	// Invalidate should not make a subsequent search error out, and a new
+	// file created afterwards should be found.
+	searcher.Invalidate(tempDir)
+
+	if err := os.WriteFile(filepath.Join(tempDir, "new-file.txt"), []byte("x"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	results, err := searcher.Search(".txt", tempDir, SearchOptions{Recursive: true})
+	if err != nil {
+		t.Fatalf("Search() error = %v", err)
+	}
+	if len(results) != 1 {
+		t.Errorf("Search() after Invalidate() = %d results, want 1", len(results))
+	}
+}
@@ -0,0 +1,103 @@
+package search
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// searchFileContent scans path line by line for matches of re, skipping
+// binary files unless mimeTypes explicitly allows their detected content
+// type. It returns one LineMatch per regex match found.
+func searchFileContent(path string, re *regexp.Regexp, mimeTypes []string) ([]LineMatch, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	sniff := make([]byte, 512)
+	n, err := f.Read(sniff)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	if !contentTypeAllowed(http.DetectContentType(sniff[:n]), mimeTypes) {
+		return nil, nil
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	var matches []LineMatch
+	lineNumber := 0
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		for _, loc := range re.FindAllStringIndex(line, -1) {
+			matches = append(matches, LineMatch{
+				LineNumber: lineNumber,
+				Line:       line,
+				Start:      loc[0],
+				End:        loc[1],
+			})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return matches, nil
+}
+
+// matchContentBytes runs the same sniff-then-scan logic as
+// searchFileContent against in-memory data, for callers (like the archive
+// backend) that can't open an *os.File directly.
+func matchContentBytes(data []byte, re *regexp.Regexp, mimeTypes []string) []LineMatch {
+	sniffLen := 512
+	if len(data) < sniffLen {
+		sniffLen = len(data)
+	}
+	if !contentTypeAllowed(http.DetectContentType(data[:sniffLen]), mimeTypes) {
+		return nil
+	}
+
+	var matches []LineMatch
+	lineNumber := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		for _, loc := range re.FindAllStringIndex(line, -1) {
+			matches = append(matches, LineMatch{
+				LineNumber: lineNumber,
+				Line:       line,
+				Start:      loc[0],
+				End:        loc[1],
+			})
+		}
+	}
+	return matches
+}
+
+// contentTypeAllowed reports whether contentType (as sniffed by
+// http.DetectContentType) should be searched. With no explicit mimeTypes,
+// only text/* content is considered; otherwise contentType must match one
+// of the configured prefixes.
+func contentTypeAllowed(contentType string, mimeTypes []string) bool {
+	if len(mimeTypes) == 0 {
+		return strings.HasPrefix(contentType, "text/")
+	}
+	for _, mt := range mimeTypes {
+		if strings.HasPrefix(contentType, mt) {
+			return true
+		}
+	}
+	return false
+}
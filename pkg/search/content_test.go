@@ -0,0 +1,51 @@
+package search
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func TestSearchFileContent(t *testing.T) {
+	tempDir := t.TempDir()
+
+	textPath := filepath.Join(tempDir, "notes.txt")
+	if err := os.WriteFile(textPath, []byte("first line\nsecond needle line\nthird needle again\n"), 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	binaryPath := filepath.Join(tempDir, "binary.dat")
+	if err := os.WriteFile(binaryPath, []byte{0x00, 0x01, 0x02, 'n', 'e', 'e', 'd', 'l', 'e'}, 0644); err != nil {
+		t.Fatalf("failed to write test file: %v", err)
+	}
+
+	re := regexp.MustCompile("needle")
+
+	matches, err := searchFileContent(textPath, re, nil)
+	if err != nil {
+		t.Fatalf("searchFileContent() error = %v", err)
+	}
+	if len(matches) != 2 {
+		t.Fatalf("searchFileContent() = %d matches, want 2", len(matches))
+	}
+	if matches[0].LineNumber != 2 || matches[1].LineNumber != 3 {
+		t.Errorf("searchFileContent() line numbers = %d, %d, want 2, 3", matches[0].LineNumber, matches[1].LineNumber)
+	}
+
+	matches, err = searchFileContent(binaryPath, re, nil)
+	if err != nil {
+		t.Fatalf("searchFileContent() error = %v", err)
+	}
+	if len(matches) != 0 {
+		t.Errorf("searchFileContent() should skip binary files by default, got %d matches", len(matches))
+	}
+
+	matches, err = searchFileContent(binaryPath, re, []string{"application/octet-stream"})
+	if err != nil {
+		t.Fatalf("searchFileContent() error = %v", err)
+	}
+	if len(matches) != 1 {
+		t.Errorf("searchFileContent() with explicit MimeTypes = %d matches, want 1", len(matches))
+	}
+}
@@ -0,0 +1,155 @@
+// Package filter implements gitignore-style include/exclude matching for
+// file paths, used by the search walker and the CLI's -include/-exclude
+// flags.
+package filter
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// pattern is a single compiled gitignore-style rule.
+type pattern struct {
+	negate  bool           // "!pattern"
+	dirOnly bool           // "pattern/"
+	re      *regexp.Regexp // compiled matcher, relative to the search root
+}
+
+// Matcher matches slash-separated, root-relative paths against a set of
+// gitignore-style patterns. Patterns are evaluated in order and, like
+// gitignore, the last matching pattern wins (so a later "!pattern" can
+// re-include something excluded earlier).
+type Matcher struct {
+	patterns []pattern
+}
+
+// New compiles patterns into a Matcher. Blank lines and lines starting with
+// "#" are ignored, matching gitignore file conventions. Supported syntax:
+//
+//   - "**" recurses through any number of path segments
+//   - "*" and "?" match within a single segment
+//   - a leading "!" negates the pattern
+//   - a leading "/" anchors the pattern to the search root
+//   - a trailing "/" restricts the pattern to directories
+func New(patterns []string) (*Matcher, error) {
+	m := &Matcher{}
+	for _, raw := range patterns {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		p := pattern{}
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = line[1:]
+		}
+		if strings.HasSuffix(line, "/") {
+			p.dirOnly = true
+			line = strings.TrimSuffix(line, "/")
+		}
+		anchored := strings.HasPrefix(line, "/")
+		line = strings.TrimPrefix(line, "/")
+		if line == "" {
+			continue
+		}
+
+		re, err := compileGlob(line, anchored)
+		if err != nil {
+			return nil, err
+		}
+		p.re = re
+
+		m.patterns = append(m.patterns, p)
+	}
+	return m, nil
+}
+
+// Match reports whether relPath (root-relative, slash-separated) is matched
+// by the pattern set, taking negation into account. isDir indicates whether
+// relPath names a directory.
+func (m *Matcher) Match(relPath string, isDir bool) bool {
+	relPath = filepath.ToSlash(relPath)
+
+	matched := false
+	for _, p := range m.patterns {
+		if p.dirOnly && !isDir {
+			continue
+		}
+		if p.re.MatchString(relPath) {
+			matched = !p.negate
+		}
+	}
+	return matched
+}
+
+// compileGlob translates a single gitignore-style glob into a regexp
+// anchored to the full relative path.
+func compileGlob(glob string, anchored bool) (*regexp.Regexp, error) {
+	var b strings.Builder
+	b.WriteString("^")
+	if !anchored && !strings.Contains(glob, "/") {
+		// A pattern with no slash matches the basename at any depth.
+		b.WriteString("(?:.*/)?")
+	}
+
+	for i := 0; i < len(glob); {
+		switch {
+		case strings.HasPrefix(glob[i:], "**/"):
+			// "**/" matches zero or more leading path segments.
+			b.WriteString("(?:.*/)?")
+			i += 3
+		case strings.HasPrefix(glob[i:], "/**"):
+			// "/**" matches zero or more trailing path segments.
+			b.WriteString("(?:/.*)?")
+			i += 3
+		case glob[i] == '*':
+			b.WriteString("[^/]*")
+			i++
+		case glob[i] == '?':
+			b.WriteString("[^/]")
+			i++
+		default:
+			b.WriteString(regexp.QuoteMeta(string(glob[i])))
+			i++
+		}
+	}
+	b.WriteString("(?:/.*)?$")
+
+	return regexp.Compile(b.String())
+}
+
+// LoadIgnoreFile searches upward from root for a .searchbotignore file and
+// returns its patterns. It returns (nil, nil) if no such file is found.
+func LoadIgnoreFile(root string) ([]string, error) {
+	dir, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		path := filepath.Join(dir, ".searchbotignore")
+		if f, err := os.Open(path); err == nil {
+			defer f.Close()
+
+			var lines []string
+			scanner := bufio.NewScanner(f)
+			for scanner.Scan() {
+				lines = append(lines, scanner.Text())
+			}
+			if err := scanner.Err(); err != nil {
+				return nil, err
+			}
+			return lines, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
@@ -0,0 +1,83 @@
+package filter
+
+import "testing"
+
+func TestMatcherMatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		path     string
+		isDir    bool
+		want     bool
+	}{
+		{
+			name:     "simple basename glob",
+			patterns: []string{"*.go"},
+			path:     "pkg/search/search.go",
+			want:     true,
+		},
+		{
+			name:     "double star recursion",
+			patterns: []string{"**/*.go"},
+			path:     "pkg/search/search.go",
+			want:     true,
+		},
+		{
+			name:     "directory-only pattern skips files",
+			patterns: []string{"vendor/"},
+			path:     "vendor/lib.go",
+			isDir:    false,
+			want:     false,
+		},
+		{
+			name:     "directory-only pattern matches directories",
+			patterns: []string{"vendor/"},
+			path:     "vendor",
+			isDir:    true,
+			want:     true,
+		},
+		{
+			name:     "anchored pattern only matches at root",
+			patterns: []string{"/config.json"},
+			path:     "pkg/config.json",
+			want:     false,
+		},
+		{
+			name:     "unanchored basename matches anywhere",
+			patterns: []string{"config.json"},
+			path:     "pkg/config.json",
+			want:     true,
+		},
+		{
+			name:     "negation re-includes a file",
+			patterns: []string{"*.go", "!search.go"},
+			path:     "pkg/search/search.go",
+			want:     false,
+		},
+		{
+			name:     "plain directory name matches node_modules",
+			patterns: []string{"node_modules/"},
+			path:     "Projects/node/node_modules",
+			isDir:    true,
+			want:     true,
+		},
+		{
+			name:     "comments and blank lines are ignored",
+			patterns: []string{"# a comment", "", "*.pdf"},
+			path:     "Documents/report.pdf",
+			want:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := New(tt.patterns)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			if got := m.Match(tt.path, tt.isDir); got != tt.want {
+				t.Errorf("Match(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+			}
+		})
+	}
+}
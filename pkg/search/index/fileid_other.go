@@ -0,0 +1,11 @@
+//go:build !unix
+
+package index
+
+import "os"
+
+// FileIDFor has no device/inode concept on this platform, so cache entries
+// are only ever validated by mtime.
+func FileIDFor(info os.FileInfo) FileID {
+	return FileID{}
+}
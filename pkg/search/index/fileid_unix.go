@@ -0,0 +1,17 @@
+//go:build unix
+
+package index
+
+import (
+	"os"
+	"syscall"
+)
+
+// FileIDFor extracts the (device, inode) pair identifying info, so the
+// cache survives a directory rename.
+func FileIDFor(info os.FileInfo) FileID {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		return FileID{Dev: uint64(stat.Dev), Ino: uint64(stat.Ino)}
+	}
+	return FileID{}
+}
@@ -0,0 +1,157 @@
+// Package index implements a persistent, inode-keyed cache of directory
+// listings so repeat searches over unchanged trees don't have to re-stat
+// every file.
+package index
+
+import (
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileID identifies a directory by device and inode, so a cache entry
+// survives the directory being renamed (but not being deleted and
+// recreated).
+type FileID struct {
+	Dev uint64
+	Ino uint64
+}
+
+// Dirent is a single cached directory entry.
+type Dirent struct {
+	Name    string
+	Mode    os.FileMode
+	Size    int64
+	ModTime time.Time
+}
+
+// DirEntry is everything cached for one directory.
+type DirEntry struct {
+	// Path is the directory's path as of the last time it was cached,
+	// kept only so Invalidate can match a path prefix; it is not part of
+	// the cache key.
+	Path    string
+	ID      FileID
+	ModTime time.Time
+	Entries []Dirent
+}
+
+// Index is a persistent, in-memory cache of directory listings keyed by
+// (device, inode) pair, so renaming a directory doesn't evict its cache
+// entry. Platforms where FileIDFor can't determine a device/inode pair
+// (see fileid_other.go) fall back to keying on the directory path as given
+// instead.
+// It is safe for concurrent use.
+type Index struct {
+	mu   sync.Mutex
+	path string
+	dirs map[string]DirEntry
+}
+
+// cacheKey returns the map key for dir identified by id, falling back to
+// dir itself when id is the zero value, i.e. FileIDFor couldn't report a
+// real device/inode pair.
+func cacheKey(dir string, id FileID) string {
+	if id == (FileID{}) {
+		return dir
+	}
+	return fmt.Sprintf("id:%d:%d", id.Dev, id.Ino)
+}
+
+// Load reads the index from path, if present, returning an empty index if
+// the file doesn't exist yet.
+func Load(path string) (*Index, error) {
+	idx := &Index{path: path, dirs: make(map[string]DirEntry)}
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if err := gob.NewDecoder(f).Decode(&idx.dirs); err != nil {
+		return nil, err
+	}
+	return idx, nil
+}
+
+// Get returns the cached entry for dir identified by id, if any.
+func (idx *Index) Get(dir string, id FileID) (DirEntry, bool) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	entry, ok := idx.dirs[cacheKey(dir, id)]
+	return entry, ok
+}
+
+// Set stores the entry for dir identified by id, replacing any previous
+// value.
+func (idx *Index) Set(dir string, id FileID, entry DirEntry) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.dirs[cacheKey(dir, id)] = entry
+}
+
+// Invalidate drops the cached entry for dir and everything beneath it, so
+// the next search re-reads them from disk. Entries are matched by their
+// last known path rather than the (device, inode) cache key, since that's
+// what callers of Invalidate have in hand.
+func (idx *Index) Invalidate(dir string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	dir = filepath.Clean(dir)
+	prefix := dir + string(filepath.Separator)
+	for key, entry := range idx.dirs {
+		if entry.Path == dir || strings.HasPrefix(entry.Path, prefix) {
+			delete(idx.dirs, key)
+		}
+	}
+}
+
+// Save persists the index to its configured path, creating parent
+// directories as needed. It writes to a temporary file first and renames
+// it into place so a crash mid-write can't corrupt the cache.
+func (idx *Index) Save() error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(idx.path), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(idx.path), ".index-*.gob")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+
+	if err := gob.NewEncoder(tmp).Encode(idx.dirs); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+
+	return os.Rename(tmpPath, idx.path)
+}
+
+// DefaultCachePath returns the default location for the index file, under
+// $XDG_CACHE_HOME (or the OS equivalent, via os.UserCacheDir).
+func DefaultCachePath() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(cacheDir, "searchbot", "index.gob"), nil
+}
@@ -0,0 +1,86 @@
+package index
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestIndexSaveAndLoad(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "cache", "index.gob")
+
+	idx, err := Load(cachePath)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	id := FileID{Dev: 1, Ino: 42}
+	if _, ok := idx.Get("/some/dir", id); ok {
+		t.Fatal("Get() on a fresh index should find nothing")
+	}
+
+	entry := DirEntry{
+		Path:    "/some/dir",
+		ID:      id,
+		ModTime: time.Unix(1700000000, 0),
+		Entries: []Dirent{{Name: "a.txt", Size: 10}},
+	}
+	idx.Set("/some/dir", id, entry)
+
+	if err := idx.Save(); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	reloaded, err := Load(cachePath)
+	if err != nil {
+		t.Fatalf("Load() after Save() error = %v", err)
+	}
+	got, ok := reloaded.Get("/some/dir", id)
+	if !ok {
+		t.Fatal("Get() after reload should find the saved entry")
+	}
+	if got.ID != entry.ID || len(got.Entries) != 1 || got.Entries[0].Name != "a.txt" {
+		t.Errorf("Get() = %+v, want %+v", got, entry)
+	}
+}
+
+func TestIndexGetByIDSurvivesRename(t *testing.T) {
+	idx, err := Load(filepath.Join(t.TempDir(), "index.gob"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	id := FileID{Dev: 7, Ino: 99}
+	idx.Set("/old/path", id, DirEntry{Path: "/old/path", ID: id})
+
+	if _, ok := idx.Get("/new/path", id); !ok {
+		t.Error("Get() under the new path should still find the entry cached by ID")
+	}
+}
+
+func TestIndexInvalidate(t *testing.T) {
+	idx, err := Load(filepath.Join(t.TempDir(), "index.gob"))
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	ids := map[string]FileID{
+		"/root":                  {Ino: 1},
+		"/root/child":            {Ino: 2},
+		"/root/child/grandchild": {Ino: 3},
+		"/sibling":               {Ino: 4},
+	}
+	for path, id := range ids {
+		idx.Set(path, id, DirEntry{Path: path, ID: id})
+	}
+
+	idx.Invalidate("/root")
+
+	for _, path := range []string{"/root", "/root/child", "/root/child/grandchild"} {
+		if _, ok := idx.Get(path, ids[path]); ok {
+			t.Errorf("Get(%q) should have been invalidated", path)
+		}
+	}
+	if _, ok := idx.Get("/sibling", ids["/sibling"]); !ok {
+		t.Error("Invalidate() should not have removed unrelated entries")
+	}
+}
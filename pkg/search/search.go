@@ -1,10 +1,17 @@
 package search
 
 import (
+	"context"
 	"errors"
 	"os"
 	"path/filepath"
+	"regexp"
+	"runtime"
+	"searchbot/pkg/search/filter"
+	"searchbot/pkg/vfs"
 	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // SearchOptions contains search configuration
@@ -12,6 +19,47 @@ type SearchOptions struct {
 	Recursive     bool
 	ExactMatch    bool
 	CaseSensitive bool
+
+	// Concurrency sets how many worker goroutines walk directories in
+	// parallel. Zero (the default) falls back to runtime.NumCPU().
+	Concurrency int
+
+	// IncludePatterns and ExcludePatterns are gitignore-style globs
+	// (see pkg/search/filter) evaluated against each path relative to
+	// root. When IncludePatterns is non-empty, a file must match at
+	// least one of them to be considered. ExcludePatterns are applied
+	// afterwards and always win; an excluded directory is not descended
+	// into.
+	IncludePatterns []string
+	ExcludePatterns []string
+
+	// Content, when set, is a regular expression searched for inside
+	// each filename-matching candidate, line by line. Only files with
+	// at least one match are returned, and SearchResult.Matches is
+	// populated with where each match occurred.
+	Content string
+
+	// MaxFileSize skips content search for files larger than this many
+	// bytes. Zero means no limit.
+	MaxFileSize int64
+
+	// MimeTypes restricts content search to files whose sniffed MIME
+	// type (via http.DetectContentType) has one of these prefixes. When
+	// empty, any non-binary ("text/...") file is eligible.
+	MimeTypes []string
+
+	// SearchArchives enables transparently descending into .zip, .tar,
+	// .tar.gz, and .tar.bz2 files (see pkg/vfs), reporting matches
+	// inside them with paths like "/path/backup.tar.gz!inner/report.pdf".
+	SearchArchives bool
+}
+
+// LineMatch describes a single regex match found within a file during a
+// content search.
+type LineMatch struct {
+	LineNumber int
+	Line       string
+	Start, End int
 }
 
 // SearchResult represents a single file search result
@@ -20,6 +68,11 @@ type SearchResult struct {
 	Name    string
 	Size    int64
 	ModTime string
+
+	// Matches holds each content match found in the file when
+	// SearchOptions.Content was set; it is empty for filename-only
+	// searches.
+	Matches []LineMatch
 }
 
 var (
@@ -38,72 +91,278 @@ func shouldSkipDirectory(path string) bool {
 		base == "Applications" // Skip Applications
 }
 
-// SearchFiles searches for files containing the given pattern in their names
+// matchName reports whether fileName matches pattern under opts.
+func matchName(fileName, pattern string, opts SearchOptions) bool {
+	if !opts.CaseSensitive {
+		fileName = strings.ToLower(fileName)
+		pattern = strings.ToLower(pattern)
+	}
+	if opts.ExactMatch {
+		return fileName == pattern
+	}
+	return strings.Contains(fileName, pattern)
+}
+
+// SearchFiles searches for files containing the given pattern in their names.
+// It walks the tree with a concurrent worker pool (see SearchFilesStream) and
+// collects every result before returning.
 func SearchFiles(pattern string, root string, opts SearchOptions) ([]SearchResult, error) {
-	// Validate inputs
+	resultCh, errCh := SearchFilesStream(context.Background(), pattern, root, opts)
+
+	var results []SearchResult
+	for result := range resultCh {
+		results = append(results, result)
+	}
+
+	if err := <-errCh; err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// dirJob is a directory queued up for a worker to read.
+type dirJob struct {
+	path   string
+	isRoot bool
+}
+
+// SearchFilesStream searches for files matching pattern under root using a
+// bounded pool of worker goroutines, streaming results as they're found.
+//
+// A single producer/collector goroutine enumerates directories through an
+// internal queue; opts.Concurrency workers (default runtime.NumCPU()) pop
+// directories, read their entries with os.ReadDir, match files against
+// pattern, and push any subdirectories back onto the queue. Both returned
+// channels are closed once the walk finishes, ctx is canceled, or a fatal
+// error occurs.
+func SearchFilesStream(ctx context.Context, pattern string, root string, opts SearchOptions) (<-chan SearchResult, <-chan error) {
+	resultCh := make(chan SearchResult)
+	errCh := make(chan error, 1)
+
 	if pattern == "" {
-		return nil, ErrEmptyPattern
+		close(resultCh)
+		errCh <- ErrEmptyPattern
+		close(errCh)
+		return resultCh, errCh
 	}
 
-	// Check if root exists
 	if _, err := os.Stat(root); err != nil {
-		return nil, ErrInvalidPath
+		close(resultCh)
+		errCh <- ErrInvalidPath
+		close(errCh)
+		return resultCh, errCh
 	}
 
-	var results []SearchResult
+	includeMatcher, err := filter.New(opts.IncludePatterns)
+	if err != nil {
+		close(resultCh)
+		errCh <- err
+		close(errCh)
+		return resultCh, errCh
+	}
+	excludeMatcher, err := filter.New(opts.ExcludePatterns)
+	if err != nil {
+		close(resultCh)
+		errCh <- err
+		close(errCh)
+		return resultCh, errCh
+	}
 
-	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+	var contentRe *regexp.Regexp
+	if opts.Content != "" {
+		contentRe, err = regexp.Compile(opts.Content)
 		if err != nil {
-			return nil // Skip files we can't access
+			close(resultCh)
+			errCh <- err
+			close(errCh)
+			return resultCh, errCh
+		}
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = runtime.NumCPU()
+	}
+
+	jobs := make(chan dirJob, concurrency*4)
+	var inFlight int64
+	var wg sync.WaitGroup
+
+	// finishJob marks one queued directory as done, closing jobs once
+	// every queued directory has either been processed or abandoned due
+	// to cancellation. It must be the only place inFlight is decremented,
+	// so jobs gets closed exactly once no matter which path gets there
+	// last.
+	finishJob := func() {
+		if atomic.AddInt64(&inFlight, -1) == 0 {
+			close(jobs)
 		}
+	}
 
-		// Handle directories
-		if info.IsDir() {
-			if path != root && !opts.Recursive {
-				return filepath.SkipDir
+	// queue hands a directory off to the dispatcher goroutine below rather
+	// than spawning one goroutine per call: a tree with millions of
+	// directories would otherwise leave millions of goroutines parked on
+	// the jobs channel send.
+	var qmu sync.Mutex
+	var pending []dirJob
+	notify := make(chan struct{}, 1)
+	dispatcherDone := make(chan struct{})
+
+	queue := func(job dirJob) {
+		atomic.AddInt64(&inFlight, 1)
+		qmu.Lock()
+		pending = append(pending, job)
+		qmu.Unlock()
+		select {
+		case notify <- struct{}{}:
+		default:
+		}
+	}
+
+	go func() {
+		for {
+			qmu.Lock()
+			if len(pending) == 0 {
+				qmu.Unlock()
+				select {
+				case <-notify:
+					continue
+				case <-dispatcherDone:
+					return
+				}
+			}
+			job := pending[0]
+			pending = pending[1:]
+			qmu.Unlock()
+
+			select {
+			case jobs <- job:
+			case <-ctx.Done():
+				finishJob()
+			case <-dispatcherDone:
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				processDir(ctx, root, job, pattern, opts, includeMatcher, excludeMatcher, contentRe, resultCh, queue)
+				finishJob()
+			}
+		}()
+	}
+
+	queue(dirJob{path: root, isRoot: true})
+
+	go func() {
+		wg.Wait()
+		close(dispatcherDone)
+		close(resultCh)
+		errCh <- ctx.Err()
+		close(errCh)
+	}()
+
+	return resultCh, errCh
+}
+
+// processDir reads a single directory's entries, emitting matches and
+// re-queuing subdirectories for further processing.
+func processDir(ctx context.Context, root string, job dirJob, pattern string, opts SearchOptions, includeMatcher, excludeMatcher *filter.Matcher, contentRe *regexp.Regexp, resultCh chan<- SearchResult, queue func(dirJob)) {
+	select {
+	case <-ctx.Done():
+		return
+	default:
+	}
+
+	if !job.isRoot && shouldSkipDirectory(job.path) {
+		return
+	}
+
+	entries, err := os.ReadDir(job.path)
+	if err != nil {
+		// Skip directories we can't access, matching the previous
+		// filepath.Walk behavior.
+		return
+	}
+
+	for _, entry := range entries {
+		path := filepath.Join(job.path, entry.Name())
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			relPath = path
+		}
+
+		if entry.IsDir() {
+			if !opts.Recursive {
+				continue
 			}
 			if shouldSkipDirectory(path) {
-				return filepath.SkipDir
+				continue
+			}
+			// A directory-level exclude short-circuits descent entirely,
+			// rather than merely filtering files found underneath it.
+			if excludeMatcher.Match(relPath, true) {
+				continue
 			}
-			return nil
+			queue(dirJob{path: path})
+			continue
 		}
 
-		// Skip hidden files
-		if strings.HasPrefix(info.Name(), ".") {
-			return nil
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
 		}
 
-		// Prepare strings for comparison
-		fileName := info.Name()
-		searchPattern := pattern
+		if opts.SearchArchives {
+			if _, ok := vfs.OpenerFor(entry.Name()); ok {
+				searchArchiveFile(ctx, path, pattern, opts, contentRe, resultCh)
+			}
+		}
 
-		if !opts.CaseSensitive {
-			fileName = strings.ToLower(fileName)
-			searchPattern = strings.ToLower(searchPattern)
+		if !matchName(entry.Name(), pattern, opts) {
+			continue
 		}
 
-		// Check if file matches
-		var matches bool
-		if opts.ExactMatch {
-			matches = fileName == searchPattern
-		} else {
-			matches = strings.Contains(fileName, searchPattern)
+		if len(opts.IncludePatterns) > 0 && !includeMatcher.Match(relPath, false) {
+			continue
+		}
+		if excludeMatcher.Match(relPath, false) {
+			continue
 		}
 
-		if matches {
-			results = append(results, SearchResult{
-				Path:    path,
-				Name:    info.Name(),
-				Size:    info.Size(),
-				ModTime: info.ModTime().Format("2006-01-02 15:04:05"),
-			})
+		info, err := entry.Info()
+		if err != nil {
+			continue
 		}
-		return nil
-	})
 
-	if err != nil {
-		return nil, err
-	}
+		var matches []LineMatch
+		if contentRe != nil {
+			if opts.MaxFileSize > 0 && info.Size() > opts.MaxFileSize {
+				continue
+			}
+			matches, err = searchFileContent(path, contentRe, opts.MimeTypes)
+			if err != nil {
+				continue
+			}
+			if len(matches) == 0 {
+				continue
+			}
+		}
 
-	return results, nil
+		select {
+		case resultCh <- SearchResult{
+			Path:    path,
+			Name:    entry.Name(),
+			Size:    info.Size(),
+			ModTime: info.ModTime().Format("2006-01-02 15:04:05"),
+			Matches: matches,
+		}:
+		case <-ctx.Done():
+			return
+		}
+	}
 }
@@ -1,9 +1,13 @@
 package search
 
 import (
+	"archive/zip"
+	"bytes"
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 // createTestFiles creates a temporary directory with realistic test files
@@ -237,6 +241,172 @@ func TestSearchFiles(t *testing.T) {
 	}
 }
 
+func TestSearchFilesStream(t *testing.T) {
+	tempDir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	opts := SearchOptions{Recursive: true, CaseSensitive: true, Concurrency: 2}
+	resultCh, errCh := SearchFilesStream(context.Background(), ".pdf", tempDir, opts)
+
+	foundFiles := make(map[string]bool)
+	for result := range resultCh {
+		foundFiles[filepath.Base(result.Path)] = true
+	}
+
+	if err := <-errCh; err != nil {
+		t.Fatalf("SearchFilesStream() error = %v", err)
+	}
+
+	for _, expected := range []string{"report-2024.pdf", "résumé.pdf"} {
+		if !foundFiles[expected] {
+			t.Errorf("SearchFilesStream() should contain %s, but didn't", expected)
+		}
+	}
+}
+
+func TestSearchFilesStreamCancellation(t *testing.T) {
+	tempDir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	resultCh, errCh := SearchFilesStream(ctx, "2024", tempDir, SearchOptions{Recursive: true})
+
+	for range resultCh {
+		// Drain; cancellation may still let a few in-flight results through.
+	}
+
+	select {
+	case <-errCh:
+	case <-time.After(time.Second):
+		t.Fatal("SearchFilesStream() did not close error channel after cancellation")
+	}
+}
+
+func TestSearchFilesWithPatternFilters(t *testing.T) {
+	tempDir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	results, err := SearchFiles("2024", tempDir, SearchOptions{
+		Recursive:       true,
+		CaseSensitive:   true,
+		ExcludePatterns: []string{"*.jpg"},
+	})
+	if err != nil {
+		t.Fatalf("SearchFiles() error = %v", err)
+	}
+
+	foundFiles := make(map[string]bool)
+	for _, result := range results {
+		foundFiles[filepath.Base(result.Path)] = true
+	}
+
+	if foundFiles["vacation2024.jpg"] {
+		t.Errorf("SearchFiles() should have excluded vacation2024.jpg, but didn't")
+	}
+	if !foundFiles["report-2024.pdf"] {
+		t.Errorf("SearchFiles() should still contain report-2024.pdf")
+	}
+
+	results, err = SearchFiles("2024", tempDir, SearchOptions{
+		Recursive:       true,
+		CaseSensitive:   true,
+		IncludePatterns: []string{"*.pdf"},
+	})
+	if err != nil {
+		t.Fatalf("SearchFiles() error = %v", err)
+	}
+
+	for _, result := range results {
+		if filepath.Ext(result.Path) != ".pdf" {
+			t.Errorf("SearchFiles() with IncludePatterns returned non-pdf file %s", result.Path)
+		}
+	}
+}
+
+func TestSearchFilesContent(t *testing.T) {
+	tempDir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	results, err := SearchFiles(".txt", tempDir, SearchOptions{
+		Recursive:     true,
+		CaseSensitive: true,
+		Content:       "notes",
+	})
+	if err != nil {
+		t.Fatalf("SearchFiles() error = %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("SearchFiles() with Content = %d results, want 1", len(results))
+	}
+	if filepath.Base(results[0].Path) != "meeting-notes.txt" {
+		t.Errorf("SearchFiles() matched %s, want meeting-notes.txt", results[0].Path)
+	}
+	if len(results[0].Matches) != 1 {
+		t.Fatalf("SearchFiles() found %d content matches, want 1", len(results[0].Matches))
+	}
+	if results[0].Matches[0].Line != "meeting notes" {
+		t.Errorf("SearchFiles() matched line = %q, want %q", results[0].Matches[0].Line, "meeting notes")
+	}
+}
+
+func TestSearchFilesContentNoMatch(t *testing.T) {
+	tempDir, cleanup := createTestFiles(t)
+	defer cleanup()
+
+	results, err := SearchFiles(".txt", tempDir, SearchOptions{
+		Recursive:     true,
+		CaseSensitive: true,
+		Content:       "this pattern does not appear anywhere",
+	})
+	if err != nil {
+		t.Fatalf("SearchFiles() error = %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("SearchFiles() with no content match = %d results, want 0", len(results))
+	}
+}
+
+func TestSearchFilesArchives(t *testing.T) {
+	tempDir := t.TempDir()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("inner/report.txt")
+	if err != nil {
+		t.Fatalf("failed to create zip entry: %v", err)
+	}
+	if _, err := w.Write([]byte("the needle is here")); err != nil {
+		t.Fatalf("failed to write zip entry: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close zip writer: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tempDir, "backup.zip"), buf.Bytes(), 0644); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+
+	results, err := SearchFiles(".txt", tempDir, SearchOptions{
+		Recursive:      true,
+		CaseSensitive:  true,
+		Content:        "needle",
+		SearchArchives: true,
+	})
+	if err != nil {
+		t.Fatalf("SearchFiles() error = %v", err)
+	}
+
+	if len(results) != 1 {
+		t.Fatalf("SearchFiles() with SearchArchives = %d results, want 1", len(results))
+	}
+	wantPath := filepath.Join(tempDir, "backup.zip") + "!inner/report.txt"
+	if results[0].Path != wantPath {
+		t.Errorf("SearchFiles() path = %q, want %q", results[0].Path, wantPath)
+	}
+}
+
 func TestSearchFilesErrors(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -0,0 +1,81 @@
+package vfs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// memEntry is a single file held fully in memory, as produced by archive
+// backends that can't otherwise expose random access to their contents.
+type memEntry struct {
+	name    string
+	data    []byte
+	size    int64
+	modTime time.Time
+	isDir   bool
+}
+
+func (e memEntry) Name() string       { return path.Base(e.name) }
+func (e memEntry) Size() int64        { return e.size }
+func (e memEntry) ModTime() time.Time { return e.modTime }
+func (e memEntry) IsDir() bool        { return e.isDir }
+
+// memFS is a read-only FS backed by a flat map of entries, keyed by
+// slash-separated path. It backs the tar archive backends; zip archives use
+// zipFS instead.
+type memFS struct {
+	entries map[string]memEntry
+}
+
+func newMemFS() *memFS {
+	return &memFS{entries: make(map[string]memEntry)}
+}
+
+func (m *memFS) add(name string, data []byte, modTime time.Time, isDir bool) {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	m.entries[name] = memEntry{name: name, data: data, size: int64(len(data)), modTime: modTime, isDir: isDir}
+}
+
+func (m *memFS) Walk(root string, fn WalkFunc) error {
+	root = strings.TrimPrefix(path.Clean("/"+root), "/")
+
+	names := make([]string, 0, len(m.entries))
+	for name := range m.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if root != "." && root != "" && name != root && !strings.HasPrefix(name, root+"/") {
+			continue
+		}
+		entry := m.entries[name]
+		if err := fn(name, entry, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *memFS) Open(name string) (io.ReadCloser, error) {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	entry, ok := m.entries[name]
+	if !ok || entry.isDir {
+		return nil, fmt.Errorf("vfs: no such file %q", name)
+	}
+	return io.NopCloser(bytes.NewReader(entry.data)), nil
+}
+
+func (m *memFS) Stat(name string) (FileInfo, error) {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	entry, ok := m.entries[name]
+	if !ok {
+		return nil, fmt.Errorf("vfs: no such file %q", name)
+	}
+	return entry, nil
+}
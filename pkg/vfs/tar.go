@@ -0,0 +1,66 @@
+package vfs
+
+import (
+	"archive/tar"
+	"compress/bzip2"
+	"compress/gzip"
+	"io"
+)
+
+func init() {
+	Register(".tar", openTar)
+	Register(".tar.gz", openTarGz)
+	Register(".tar.bz2", openTarBz2)
+}
+
+// openTar reads an uncompressed tar archive fully into an in-memory FS.
+// Unlike zip, tar has no central directory to seek to, so a single
+// sequential pass is the only way to enumerate its entries regardless of
+// whether the underlying reader supports random access.
+func openTar(r io.ReaderAt, size int64) (FS, error) {
+	return readTar(io.NewSectionReader(r, 0, size))
+}
+
+func openTarGz(r io.ReaderAt, size int64) (FS, error) {
+	gr, err := gzip.NewReader(io.NewSectionReader(r, 0, size))
+	if err != nil {
+		return nil, err
+	}
+	defer gr.Close()
+	return readTar(gr)
+}
+
+func openTarBz2(r io.ReaderAt, size int64) (FS, error) {
+	return readTar(bzip2.NewReader(io.NewSectionReader(r, 0, size)))
+}
+
+func readTar(r io.Reader) (FS, error) {
+	fs := newMemFS()
+	tr := tar.NewReader(r)
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if hdr.Typeflag == tar.TypeDir {
+			fs.add(hdr.Name, nil, hdr.ModTime, true)
+			continue
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		data := make([]byte, hdr.Size)
+		if _, err := io.ReadFull(tr, data); err != nil {
+			return nil, err
+		}
+		fs.add(hdr.Name, data, hdr.ModTime, false)
+	}
+
+	return fs, nil
+}
@@ -0,0 +1,113 @@
+// Package vfs abstracts file access behind a small filesystem interface so
+// the search walker can descend into archive files the same way it
+// descends into directories. OSFS is the default, backed by the real
+// filesystem; archive backends (see zip.go, tar.go) register themselves by
+// file extension via Register.
+package vfs
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileInfo describes a single entry in an FS, independent of whether it
+// came from the real filesystem or an archive.
+type FileInfo interface {
+	Name() string
+	Size() int64
+	ModTime() time.Time
+	IsDir() bool
+}
+
+// WalkFunc is called for each entry visited by FS.Walk: returning an error
+// stops the walk. OSFS honors filepath.SkipDir as filepath.Walk does; the
+// zip and tar backends do not treat it specially, so it stops their walk
+// like any other error.
+type WalkFunc func(path string, info FileInfo, err error) error
+
+// FS abstracts read access to a tree of files, whether it's the OS
+// filesystem or the contents of an archive.
+type FS interface {
+	Walk(root string, fn WalkFunc) error
+	Open(path string) (io.ReadCloser, error)
+	Stat(path string) (FileInfo, error)
+}
+
+// Opener builds an FS over an archive's raw bytes. size is the total
+// length of the archive, as required by formats (like zip) that need to
+// seek to a trailer.
+type Opener func(r io.ReaderAt, size int64) (FS, error)
+
+var openers = map[string]Opener{}
+
+// Register associates an opener with a file extension (e.g. ".zip",
+// ".tar.gz"), so the walker can recognize and descend into archives of
+// that type. Extensions are matched case-insensitively against the
+// filename suffix.
+func Register(ext string, opener Opener) {
+	openers[filepathToLower(ext)] = opener
+}
+
+// OpenerFor returns the opener registered for name's extension, if any.
+// Multi-part extensions (".tar.gz") are checked before the final
+// single-part extension (".gz"): ranging over openers directly would
+// match whichever registered suffix Go's map iteration happened to visit
+// first, so instead every registered extension is checked and the
+// longest matching suffix wins.
+func OpenerFor(name string) (Opener, bool) {
+	lower := filepathToLower(name)
+
+	var bestExt string
+	var bestOpener Opener
+	found := false
+	for ext, opener := range openers {
+		if len(lower) > len(ext) && lower[len(lower)-len(ext):] == ext && len(ext) > len(bestExt) {
+			bestExt = ext
+			bestOpener = opener
+			found = true
+		}
+	}
+	return bestOpener, found
+}
+
+func filepathToLower(name string) string {
+	b := []byte(name)
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c + ('a' - 'A')
+		}
+	}
+	return string(b)
+}
+
+// osFileInfo adapts os.FileInfo to FileInfo.
+type osFileInfo struct{ os.FileInfo }
+
+// OSFS is the default FS, backed by the real filesystem.
+type OSFS struct{}
+
+// Walk implements FS over filepath.Walk.
+func (OSFS) Walk(root string, fn WalkFunc) error {
+	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return fn(path, nil, err)
+		}
+		return fn(path, osFileInfo{info}, nil)
+	})
+}
+
+// Open implements FS.
+func (OSFS) Open(path string) (io.ReadCloser, error) {
+	return os.Open(path)
+}
+
+// Stat implements FS.
+func (OSFS) Stat(path string) (FileInfo, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	return osFileInfo{info}, nil
+}
@@ -0,0 +1,140 @@
+package vfs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func buildZip(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	w, err := zw.Create("inner/report.txt")
+	if err != nil {
+		t.Fatalf("failed to create zip entry: %v", err)
+	}
+	if _, err := w.Write([]byte("needle in a zip")); err != nil {
+		t.Fatalf("failed to write zip entry: %v", err)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to close zip writer: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func buildTar(t *testing.T) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+
+	content := []byte("needle in a tar")
+	hdr := &tar.Header{Name: "inner/report.txt", Mode: 0644, Size: int64(len(content)), ModTime: time.Unix(0, 0)}
+	if err := tw.WriteHeader(hdr); err != nil {
+		t.Fatalf("failed to write tar header: %v", err)
+	}
+	if _, err := tw.Write(content); err != nil {
+		t.Fatalf("failed to write tar content: %v", err)
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatalf("failed to close tar writer: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestZipFS(t *testing.T) {
+	data := buildZip(t)
+	opener, ok := OpenerFor("backup.zip")
+	if !ok {
+		t.Fatal("OpenerFor(backup.zip) not registered")
+	}
+
+	fsys, err := opener(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("opener() error = %v", err)
+	}
+
+	rc, err := fsys.Open("inner/report.txt")
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer rc.Close()
+
+	got, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if string(got) != "needle in a zip" {
+		t.Errorf("Open() content = %q, want %q", got, "needle in a zip")
+	}
+}
+
+func TestTarFS(t *testing.T) {
+	data := buildTar(t)
+	opener, ok := OpenerFor("backup.tar")
+	if !ok {
+		t.Fatal("OpenerFor(backup.tar) not registered")
+	}
+
+	fsys, err := opener(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("opener() error = %v", err)
+	}
+
+	var seen []string
+	err = fsys.Walk(".", func(path string, info FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			seen = append(seen, path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Walk() error = %v", err)
+	}
+	if len(seen) != 1 || seen[0] != "inner/report.txt" {
+		t.Errorf("Walk() visited %v, want [inner/report.txt]", seen)
+	}
+}
+
+func TestOpenerForUnregisteredExtension(t *testing.T) {
+	if _, ok := OpenerFor("notes.txt"); ok {
+		t.Error("OpenerFor(notes.txt) should not be registered")
+	}
+}
+
+func TestOpenerForPrefersLongestSuffix(t *testing.T) {
+	gzOpener := func(io.ReaderAt, int64) (FS, error) { return nil, nil }
+
+	Register(".gz", gzOpener)
+	defer delete(openers, ".gz")
+
+	// .tar.gz is already registered by tar.go's init; run the lookup many
+	// times since a map-iteration-order bug would only misfire some of
+	// the time.
+	for i := 0; i < 50; i++ {
+		opener, ok := OpenerFor("backup.tar.gz")
+		if !ok {
+			t.Fatal("OpenerFor(backup.tar.gz) not registered")
+		}
+		if funcPtr(opener) == funcPtr(gzOpener) {
+			t.Fatalf("OpenerFor(backup.tar.gz) matched .gz instead of .tar.gz on iteration %d", i)
+		}
+	}
+
+	opener, ok := OpenerFor("archive.gz")
+	if !ok || funcPtr(opener) != funcPtr(gzOpener) {
+		t.Error("OpenerFor(archive.gz) should match the .gz opener")
+	}
+}
+
+func funcPtr(o Opener) uintptr {
+	return reflect.ValueOf(o).Pointer()
+}
@@ -0,0 +1,74 @@
+package vfs
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+func init() {
+	Register(".zip", openZip)
+}
+
+// zipFileInfo adapts zip.FileHeader to FileInfo.
+type zipFileInfo struct{ f *zip.File }
+
+func (i zipFileInfo) Name() string { return path.Base(strings.TrimSuffix(i.f.Name, "/")) }
+func (i zipFileInfo) Size() int64  { return int64(i.f.UncompressedSize64) }
+func (i zipFileInfo) ModTime() time.Time {
+	return i.f.Modified
+}
+func (i zipFileInfo) IsDir() bool { return i.f.FileInfo().IsDir() }
+
+// zipFS is an FS backed by archive/zip, which supports random access
+// directly from the underlying io.ReaderAt without buffering the archive
+// into memory.
+type zipFS struct {
+	zr *zip.Reader
+}
+
+func openZip(r io.ReaderAt, size int64) (FS, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	return &zipFS{zr: zr}, nil
+}
+
+func (z *zipFS) Walk(root string, fn WalkFunc) error {
+	root = strings.TrimPrefix(path.Clean("/"+root), "/")
+
+	for _, f := range z.zr.File {
+		name := strings.TrimSuffix(f.Name, "/")
+		if root != "." && root != "" && name != root && !strings.HasPrefix(name, root+"/") {
+			continue
+		}
+		if err := fn(name, zipFileInfo{f}, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (z *zipFS) Open(name string) (io.ReadCloser, error) {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	for _, f := range z.zr.File {
+		if strings.TrimSuffix(f.Name, "/") == name {
+			return f.Open()
+		}
+	}
+	return nil, fmt.Errorf("vfs: no such file %q", name)
+}
+
+func (z *zipFS) Stat(name string) (FileInfo, error) {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	for _, f := range z.zr.File {
+		if strings.TrimSuffix(f.Name, "/") == name {
+			return zipFileInfo{f}, nil
+		}
+	}
+	return nil, fmt.Errorf("vfs: no such file %q", name)
+}